@@ -8,7 +8,9 @@ package gofixbuf
 // Simple byte buffer for marshaling data.
 
 import (
+	"bytes"
 	"errors"
+	"hash"
 	"io"
 	"unicode/utf8"
 )
@@ -18,17 +20,49 @@ import (
 type Buffer struct {
 	buf       []byte            // contents are the bytes buf
 	off       int               // write at &buf[off]
+	roff      int               // read at &buf[roff], always <= off
+	lastRead  readOp            // last read operation, for UnreadByte/UnreadRune
 	runeBytes [utf8.UTFMax]byte // avoid allocation of slice on each WriteByte or Rune
+
+	// Fields below are only used when the Buffer is in ring mode; see
+	// ring.go. In ring mode roff is reinterpreted as a count of bytes
+	// already read from the logical window starting at head.
+	ring bool // true if this Buffer overwrites instead of erroring on overflow
+	head int  // index of the oldest valid byte
+	tail int  // index one past the newest valid byte
+	full bool // true if the ring has wrapped and every byte is valid
+
+	hash hash.Hash // optional running hash fed by every successful write; see hash.go
 }
 
+// readOp records the most recent read operation performed on a Buffer, so
+// that UnreadByte and UnreadRune know how far to rewind roff.
+type readOp int8
+
+const (
+	opInvalid   readOp = 0
+	opRead      readOp = -1
+	opReadRune1 readOp = 1
+	opReadRune2 readOp = 2
+	opReadRune3 readOp = 3
+	opReadRune4 readOp = 4
+)
+
 // ErrTooLarge is passed to panic if memory cannot be allocated to store data in a buffer.
 var ErrTooLarge = errors.New("gofixbuf.Buffer: too large")
 
 // Bytes returns a slice of the contents of the buffer;
 // len(b.Bytes()) == b.Len().  If the caller changes the contents of the
 // returned slice, the contents of the buffer will change provided there
-// are no intervening method calls on the Buffer.
-func (b *Buffer) Bytes() []byte { return b.buf[:] }
+// are no intervening method calls on the Buffer. In ring mode it instead
+// returns a freshly allocated copy of the logical contents, oldest byte
+// first, so changes to the returned slice do not affect the buffer.
+func (b *Buffer) Bytes() []byte {
+	if b.ring {
+		return b.ringBytes()
+	}
+	return b.buf[:]
+}
 
 // String returns the contents of the buffer
 // as a string.  If the Buffer is a nil pointer, it returns "<nil>".
@@ -37,20 +71,39 @@ func (b *Buffer) String() string {
 		// Special case, useful in debugging.
 		return "<nil>"
 	}
+	if b.ring {
+		return string(b.ringBytes())
+	}
 	return string(b.buf)
 }
 
-// Len returns the number of bytes in the buffer;
-// b.Len() == len(b.Bytes()).
-func (b *Buffer) Len() int { return len(b.buf) }
+// Len returns the number of bytes in the buffer; b.Len() == len(b.Bytes()).
+// In ring mode it returns the number of logically valid bytes currently
+// held, which is always between 0 and Cap() inclusive.
+func (b *Buffer) Len() int {
+	if b.ring {
+		return b.ringLen()
+	}
+	return len(b.buf)
+}
 
 func (b *Buffer) Cap() int {
 	return cap(b.buf)
 }
 
-// Reset resets the buffer so it represents the full slice.
+// Reset resets the buffer so it represents the full slice, rewinding
+// both the write offset and the read cursor. In ring mode it also empties
+// the ring.
 func (b *Buffer) Reset() {
 	b.off = 0
+	b.roff = 0
+	b.lastRead = opInvalid
+	b.head = 0
+	b.tail = 0
+	b.full = false
+	if b.hash != nil {
+		b.hash.Reset()
+	}
 }
 
 // checkLen checks that the buffer has space for n more bytes.
@@ -67,33 +120,57 @@ func (b *Buffer) checkLen(n int) (int, error) {
 // needed. The return value n is the length of p; err is always nil. If the
 // buffer becomes too large, Write will panic with ErrTooLarge.
 func (b *Buffer) Write(p []byte) (n int, err error) {
+	if b.ring {
+		n, err = b.writeRing(p)
+		b.feedHash(p)
+		return n, err
+	}
 	m, e := b.checkLen(len(p))
 	if e != nil {
 		return 0, e
 	}
 	b.off = m + len(p)
-	return copy(b.buf[m:], p), nil
+	n = copy(b.buf[m:], p)
+	b.feedHash(p)
+	return n, nil
 }
 
 // WriteString appends the contents of s to the buffer, growing the buffer as
 // needed. The return value n is the length of s; err is always nil. If the
 // buffer becomes too large, WriteString will panic with ErrTooLarge.
 func (b *Buffer) WriteString(s string) (n int, err error) {
+	if b.ring {
+		n, err = b.writeRing([]byte(s))
+		if b.hash != nil {
+			b.feedHash([]byte(s))
+		}
+		return n, err
+	}
 	m, e := b.checkLen(len(s))
 	if e != nil {
 		return 0, e
 	}
 	b.off = m + len(s)
-	return copy(b.buf[m:], s), nil
+	n = copy(b.buf[m:], s)
+	if b.hash != nil {
+		b.feedHash([]byte(s))
+	}
+	return n, nil
 }
 
 // ReadFrom reads data from r until EOF and appends it to the buffer, growing
 // the buffer as needed. The return value n is the number of bytes read. Any
 // error except io.EOF encountered during the read is also returned. If the
-// buffer becomes too large, ReadFrom will panic with ErrTooLarge.
+// buffer becomes too large, ReadFrom will panic with ErrTooLarge. In ring
+// mode ReadFrom instead keeps pulling from r until EOF, silently
+// overwriting the oldest bytes as needed.
 func (b *Buffer) ReadFrom(r io.Reader) (n int64, err error) {
+	if b.ring {
+		return b.readFromRing(r)
+	}
 	for {
 		m, e := r.Read(b.buf[b.off:cap(b.buf)])
+		b.feedHash(b.buf[b.off : b.off+m])
 		b.off += m
 		n += int64(m)
 		if e != nil || m == 0 {
@@ -102,17 +179,42 @@ func (b *Buffer) ReadFrom(r io.Reader) (n int64, err error) {
 	}
 }
 
+// WriteTo writes the buffer's unread contents to w. In ring mode it emits
+// the two contiguous segments of the ring, oldest first, stitched into a
+// single logical stream.
+func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
+	if b.ring {
+		return b.writeToRing(w)
+	}
+	if b.roff >= b.off {
+		return 0, nil
+	}
+	n, err := w.Write(b.buf[b.roff:b.off])
+	b.roff += n
+	return int64(n), err
+}
+
 // WriteByte appends the byte c to the buffer, growing the buffer as needed.
 // The returned error is always nil, but is included to match bufio.Writer's
 // WriteByte. If the buffer becomes too large, WriteByte will panic with
 // ErrTooLarge.
 func (b *Buffer) WriteByte(c byte) error {
+	if b.ring {
+		_, err := b.writeRing([]byte{c})
+		if b.hash != nil {
+			b.feedHash([]byte{c})
+		}
+		return err
+	}
 	m, e := b.checkLen(1)
 	if e != nil {
 		return e
 	}
 	b.buf[m] = c
 	b.off++
+	if b.hash != nil {
+		b.feedHash([]byte{c})
+	}
 	return nil
 }
 
@@ -130,6 +232,218 @@ func (b *Buffer) WriteRune(r rune) (n int, err error) {
 	return n, nil
 }
 
+// Read reads the next len(p) bytes from the buffer or until the buffer's
+// written contents (up to the write offset) are exhausted. The return
+// value n is the number of bytes read; if the buffer has no unread data
+// left, Read returns 0, io.EOF unless len(p) is zero.
+func (b *Buffer) Read(p []byte) (n int, err error) {
+	if b.ring {
+		return b.readRing(p)
+	}
+	if b.roff >= b.off {
+		b.lastRead = opInvalid
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n = copy(p, b.buf[b.roff:b.off])
+	b.roff += n
+	if n > 0 {
+		b.lastRead = opRead
+	}
+	return n, nil
+}
+
+// ReadByte reads and returns the next byte from the buffer, or 0, io.EOF
+// if no unread data remains.
+func (b *Buffer) ReadByte() (byte, error) {
+	if b.ring {
+		c, err := b.readByteRing()
+		if err != nil {
+			b.lastRead = opInvalid
+			return 0, err
+		}
+		b.lastRead = opRead
+		return c, nil
+	}
+	if b.roff >= b.off {
+		b.lastRead = opInvalid
+		return 0, io.EOF
+	}
+	c := b.buf[b.roff]
+	b.roff++
+	b.lastRead = opRead
+	return c, nil
+}
+
+// UnreadByte unreads the last byte returned by ReadByte or a successful
+// Read. It returns an error if the previous operation was not such a
+// read.
+func (b *Buffer) UnreadByte() error {
+	if b.lastRead == opInvalid {
+		return errors.New("gofixbuf.Buffer: UnreadByte: previous operation was not a successful read")
+	}
+	b.lastRead = opInvalid
+	if b.roff > 0 {
+		b.roff--
+	}
+	return nil
+}
+
+// ReadRune reads and returns a single UTF-8 encoded Unicode character and
+// its size in bytes. If the bytes are invalid UTF-8, it consumes one byte
+// and returns utf8.RuneError with a size of 1.
+func (b *Buffer) ReadRune() (r rune, size int, err error) {
+	if b.ring {
+		r, size, err = b.readRuneRing()
+		if err != nil {
+			b.lastRead = opInvalid
+			return 0, 0, err
+		}
+		if size == 1 {
+			b.lastRead = opReadRune1
+		} else {
+			b.lastRead = readOp(size)
+		}
+		return r, size, nil
+	}
+	if b.roff >= b.off {
+		b.lastRead = opInvalid
+		return 0, 0, io.EOF
+	}
+	c := b.buf[b.roff]
+	if c < utf8.RuneSelf {
+		b.roff++
+		b.lastRead = opReadRune1
+		return rune(c), 1, nil
+	}
+	r, size = utf8.DecodeRune(b.buf[b.roff:b.off])
+	b.roff += size
+	b.lastRead = readOp(size)
+	return r, size, nil
+}
+
+// UnreadRune unreads the last rune returned by ReadRune. It returns an
+// error if the previous operation on the buffer was not a successful
+// ReadRune.
+func (b *Buffer) UnreadRune() error {
+	if b.lastRead <= opInvalid {
+		return errors.New("gofixbuf.Buffer: UnreadRune: previous operation was not a successful ReadRune")
+	}
+	if b.roff >= int(b.lastRead) {
+		b.roff -= int(b.lastRead)
+	}
+	b.lastRead = opInvalid
+	return nil
+}
+
+// readSlice reads until delim or the end of the written contents,
+// returning a slice referencing the buffer's underlying array. In ring
+// mode it instead delegates to readSliceRing, which returns a freshly
+// allocated slice since the logical window may wrap.
+func (b *Buffer) readSlice(delim byte) (line []byte, err error) {
+	if b.ring {
+		return b.readSliceRing(delim)
+	}
+	i := bytes.IndexByte(b.buf[b.roff:b.off], delim)
+	end := b.roff + i + 1
+	if i < 0 {
+		end = b.off
+		err = io.EOF
+	}
+	line = b.buf[b.roff:end]
+	b.roff = end
+	b.lastRead = opInvalid
+	return line, err
+}
+
+// ReadBytes reads until the first occurrence of delim in the buffer,
+// returning a slice containing the data up to and including delim. If
+// ReadBytes encounters the end of the written contents before finding
+// delim, it returns the data read so far and io.EOF.
+func (b *Buffer) ReadBytes(delim byte) (line []byte, err error) {
+	slice, err := b.readSlice(delim)
+	line = append([]byte(nil), slice...)
+	return line, err
+}
+
+// ReadString is like ReadBytes but returns a string instead of a slice.
+func (b *Buffer) ReadString(delim byte) (line string, err error) {
+	slice, err := b.readSlice(delim)
+	return string(slice), err
+}
+
+// Next returns a slice containing the next n unread bytes of the buffer,
+// advancing the read cursor as if the bytes had been returned by Read. If
+// fewer than n bytes remain unread, Next returns all of them. The slice
+// is only valid until the next call to a method that writes to or resets
+// the buffer.
+func (b *Buffer) Next(n int) []byte {
+	if b.ring {
+		data := b.nextRing(n)
+		if len(data) > 0 {
+			b.lastRead = opRead
+		} else {
+			b.lastRead = opInvalid
+		}
+		return data
+	}
+	avail := b.off - b.roff
+	if n > avail {
+		n = avail
+	}
+	if n < 0 {
+		n = 0
+	}
+	data := b.buf[b.roff : b.roff+n]
+	b.roff += n
+	if n > 0 {
+		b.lastRead = opRead
+	} else {
+		b.lastRead = opInvalid
+	}
+	return data
+}
+
+// Truncate discards all but the first n written bytes, rewinding the
+// read cursor if it has advanced past the new end. Since Bytes() and
+// String() expose the whole fixed-length backing array rather than just
+// the written prefix, the discarded tail is zeroed so it stops showing
+// through. It panics if n is negative or greater than the number of
+// bytes written so far. In ring mode, "first n" means the n oldest
+// logically valid bytes; Bytes()/String() already only expose the
+// logical window in that mode, so no zeroing is needed there.
+func (b *Buffer) Truncate(n int) {
+	b.lastRead = opInvalid
+	if b.ring {
+		b.truncateRing(n)
+		return
+	}
+	if n < 0 || n > b.off {
+		panic("gofixbuf.Buffer: truncation out of range")
+	}
+	for i := n; i < len(b.buf); i++ {
+		b.buf[i] = 0
+	}
+	b.off = n
+	if b.roff > b.off {
+		b.roff = b.off
+	}
+}
+
+// Grow ensures that there is room for n more bytes to be written to the
+// buffer. Unlike bytes.Buffer's Grow, it never reallocates to honor the
+// buffer's fixed-capacity contract: if the buffer cannot accommodate n
+// more bytes, it returns ErrTooLarge instead of panicking.
+func (b *Buffer) Grow(n int) error {
+	if n < 0 {
+		panic("gofixbuf.Buffer: Grow: negative count")
+	}
+	_, err := b.checkLen(n)
+	return err
+}
+
 // NewBuffer creates and initializes a new Buffer using buf as its initial
 // contents.  It is intended to size the internal buffer for writing. To do
 // that, buf should have the desired capacity but a length of zero.
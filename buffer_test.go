@@ -251,6 +251,173 @@ func TestLargeWriteTo(t *testing.T) {
 	}
 }
 
+func TestReadWriteRoundTrip(t *testing.T) {
+	buf := NewBuffer(make([]byte, 64))
+	buf.WriteString("hello, world")
+
+	p := make([]byte, 5)
+	n, err := buf.Read(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 5 || string(p) != "hello" {
+		t.Errorf("expected to read \"hello\"; got %q (n=%d)", p[:n], n)
+	}
+
+	rest := make([]byte, 64)
+	n, err = buf.Read(rest)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(rest[:n]) != ", world" {
+		t.Errorf("expected to read \", world\"; got %q", rest[:n])
+	}
+
+	n, err = buf.Read(rest)
+	if err != io.EOF {
+		t.Errorf("expected io.EOF; got %v (n=%d)", err, n)
+	}
+}
+
+func TestReadByteAndUnreadByte(t *testing.T) {
+	buf := NewBuffer(make([]byte, 8))
+	buf.WriteString("ab")
+
+	c, err := buf.ReadByte()
+	if err != nil || c != 'a' {
+		t.Fatalf("expected 'a', nil; got %q, %v", c, err)
+	}
+
+	if err := buf.UnreadByte(); err != nil {
+		t.Fatalf("unexpected error from UnreadByte: %s", err)
+	}
+
+	c, err = buf.ReadByte()
+	if err != nil || c != 'a' {
+		t.Fatalf("expected to re-read 'a'; got %q, %v", c, err)
+	}
+
+	if err := buf.UnreadByte(); err != nil {
+		t.Fatalf("unexpected error from UnreadByte: %s", err)
+	}
+	if err := buf.UnreadByte(); err == nil {
+		t.Error("expected error unreading twice in a row")
+	}
+}
+
+func TestReadRuneAndUnreadRune(t *testing.T) {
+	buf := NewBuffer(make([]byte, 16))
+	buf.WriteString("é!")
+
+	r, size, err := buf.ReadRune()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if r != 'é' || size != 2 {
+		t.Errorf("expected ('é', 2); got (%q, %d)", r, size)
+	}
+
+	if err := buf.UnreadRune(); err != nil {
+		t.Fatalf("unexpected error from UnreadRune: %s", err)
+	}
+
+	r, size, err = buf.ReadRune()
+	if err != nil || r != 'é' || size != 2 {
+		t.Fatalf("expected to re-read ('é', 2); got (%q, %d, %v)", r, size, err)
+	}
+
+	r, size, err = buf.ReadRune()
+	if err != nil || r != '!' || size != 1 {
+		t.Fatalf("expected ('!', 1); got (%q, %d, %v)", r, size, err)
+	}
+}
+
+func TestReadBytesAndReadString(t *testing.T) {
+	buf := NewBuffer(make([]byte, 32))
+	buf.WriteString("one,two,three")
+
+	line, err := buf.ReadBytes(',')
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(line) != "one," {
+		t.Errorf("expected \"one,\"; got %q", line)
+	}
+
+	str, err := buf.ReadString(',')
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if str != "two," {
+		t.Errorf("expected \"two,\"; got %q", str)
+	}
+
+	str, err = buf.ReadString(',')
+	if err != io.EOF {
+		t.Errorf("expected io.EOF; got %v", err)
+	}
+	if str != "three" {
+		t.Errorf("expected \"three\"; got %q", str)
+	}
+}
+
+func TestNext(t *testing.T) {
+	buf := NewBuffer(make([]byte, 16))
+	buf.WriteString("abcdef")
+
+	got := buf.Next(3)
+	if string(got) != "abc" {
+		t.Errorf("expected \"abc\"; got %q", got)
+	}
+
+	got = buf.Next(100)
+	if string(got) != "def" {
+		t.Errorf("expected \"def\"; got %q", got)
+	}
+
+	got = buf.Next(1)
+	if len(got) != 0 {
+		t.Errorf("expected no bytes left to read; got %q", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	buf := NewBuffer(make([]byte, 16))
+	buf.WriteString("abcdef")
+
+	buf.Truncate(3)
+	if buf.String() != "abc" + string(make([]byte, 13)) {
+		t.Errorf("unexpected contents after Truncate: %q", buf.String())
+	}
+
+	n, err := buf.Read(make([]byte, 16))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 unread bytes after Truncate; got %d", n)
+	}
+
+	buf.Truncate(0)
+	if buf.Len() != 16 {
+		t.Errorf("Truncate(0) should not change Len(); got %d", buf.Len())
+	}
+}
+
+func TestGrow(t *testing.T) {
+	buf := NewBuffer(make([]byte, 10))
+
+	if err := buf.Grow(5); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	buf.WriteString("12345")
+
+	if err := buf.Grow(6); err != ErrTooLarge {
+		t.Errorf("expected ErrTooLarge; got %v", err)
+	}
+}
+
 func TestRuneWrites(t *testing.T) {
 	const NRune = 1000
 	// Built a test slice while we write the data
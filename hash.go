@@ -0,0 +1,52 @@
+package gofixbuf
+
+import (
+	"hash"
+	"hash/crc32"
+)
+
+// AttachHash attaches a running hash to b. Every successful Write,
+// WriteByte, WriteString, WriteRune, and ReadFrom feeds the newly written
+// bytes into h, and Reset also resets h. This lets callers marshaling
+// framed records (length + payload + checksum) compute the trailer
+// without a second pass over the data.
+func (b *Buffer) AttachHash(h hash.Hash) {
+	b.hash = h
+}
+
+// Sum returns the current hash of all data written to b since the hash
+// was attached or b was last Reset. It returns nil if no hash has been attached via
+// AttachHash.
+func (b *Buffer) Sum() []byte {
+	if b.hash == nil {
+		return nil
+	}
+	return b.hash.Sum(nil)
+}
+
+// feedHash writes p to b's attached hash, if any. It is nil-checked on
+// the hot path so uninstrumented buffers pay zero cost.
+func (b *Buffer) feedHash(p []byte) {
+	if b.hash != nil && len(p) > 0 {
+		b.hash.Write(p)
+	}
+}
+
+// NewCRC32Buffer creates a Buffer using buf as its initial contents with
+// a CRC-32 (Castagnoli) hash attached via AttachHash.
+func NewCRC32Buffer(buf []byte) *Buffer {
+	b := NewBuffer(buf)
+	b.AttachHash(crc32.New(crc32.MakeTable(crc32.Castagnoli)))
+	return b
+}
+
+// NewXXHashBuffer creates a Buffer using buf as its initial contents with
+// an xxHash64 hash (seed 0) attached via AttachHash, for callers that
+// prefer xxHash's throughput over CRC-32. Like ThrottledBuffer's Waiter,
+// this uses a small in-package implementation (see xxhash64.go) instead
+// of a hard dependency on a third-party xxHash package.
+func NewXXHashBuffer(buf []byte) *Buffer {
+	b := NewBuffer(buf)
+	b.AttachHash(newXXHash64(0))
+	return b
+}
@@ -0,0 +1,99 @@
+package gofixbuf_test
+
+import (
+	. "github.com/waucka/gofixbuf"
+	"hash/crc32"
+	"testing"
+)
+
+func TestAttachHashAndSum(t *testing.T) {
+	buf := NewBuffer(make([]byte, 16))
+	buf.AttachHash(crc32.NewIEEE())
+
+	buf.WriteString("hello")
+
+	want := crc32.ChecksumIEEE([]byte("hello"))
+	got := buf.Sum()
+	if len(got) != 4 {
+		t.Fatalf("expected a 4-byte CRC-32 sum; got %d bytes", len(got))
+	}
+	gotVal := uint32(got[0])<<24 | uint32(got[1])<<16 | uint32(got[2])<<8 | uint32(got[3])
+	if gotVal != want {
+		t.Errorf("expected sum %08x; got %08x", want, gotVal)
+	}
+}
+
+func TestSumWithoutAttachedHash(t *testing.T) {
+	buf := NewBuffer(make([]byte, 8))
+	buf.WriteString("abc")
+
+	if sum := buf.Sum(); sum != nil {
+		t.Errorf("expected nil sum with no hash attached; got %v", sum)
+	}
+}
+
+func TestResetResetsHash(t *testing.T) {
+	buf := NewBuffer(make([]byte, 16))
+	buf.AttachHash(crc32.NewIEEE())
+	buf.WriteString("hello")
+
+	buf.Reset()
+	buf.WriteString("world")
+
+	want := crc32.ChecksumIEEE([]byte("world"))
+	got := buf.Sum()
+	gotVal := uint32(got[0])<<24 | uint32(got[1])<<16 | uint32(got[2])<<8 | uint32(got[3])
+	if gotVal != want {
+		t.Errorf("expected sum of \"world\" (%08x) after Reset; got %08x", want, gotVal)
+	}
+}
+
+func TestNewCRC32Buffer(t *testing.T) {
+	buf := NewCRC32Buffer(make([]byte, 16))
+	buf.WriteString("hello")
+
+	want := crc32.Checksum([]byte("hello"), crc32.MakeTable(crc32.Castagnoli))
+	got := buf.Sum()
+	gotVal := uint32(got[0])<<24 | uint32(got[1])<<16 | uint32(got[2])<<8 | uint32(got[3])
+	if gotVal != want {
+		t.Errorf("expected sum %08x; got %08x", want, gotVal)
+	}
+}
+
+func sum64(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func TestNewXXHashBufferEmptyInput(t *testing.T) {
+	buf := NewXXHashBuffer(nil)
+
+	const want = 0xEF46DB3751D8E999
+	if got := sum64(buf.Sum()); got != want {
+		t.Errorf("expected xxHash64(\"\", seed=0) == %016x; got %016x", uint64(want), got)
+	}
+}
+
+func TestNewXXHashBufferMatchesSingleVsChunkedWrites(t *testing.T) {
+	const text = "the quick brown fox jumps over the lazy dog"
+
+	whole := NewXXHashBuffer(make([]byte, 64))
+	if _, err := whole.WriteString(text); err != nil {
+		t.Fatalf("unexpected error writing whole string: %s", err)
+	}
+
+	chunked := NewXXHashBuffer(make([]byte, 64))
+	if _, err := chunked.WriteString("the quick brown fox "); err != nil {
+		t.Fatalf("unexpected error writing first chunk: %s", err)
+	}
+	if _, err := chunked.WriteString("jumps over the lazy dog"); err != nil {
+		t.Fatalf("unexpected error writing second chunk: %s", err)
+	}
+
+	if sum64(whole.Sum()) != sum64(chunked.Sum()) {
+		t.Errorf("expected identical hash regardless of write chunking; got %x vs %x", whole.Sum(), chunked.Sum())
+	}
+}
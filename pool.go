@@ -0,0 +1,145 @@
+package gofixbuf
+
+import (
+	"io"
+	"sync"
+)
+
+// Size classes used by the PooledBuffer pools, ranging from 512B to 4MiB
+// in power-of-two steps.
+const (
+	minPooledSize = 512
+	maxPooledSize = 4 * 1024 * 1024
+)
+
+var (
+	poolSizeClasses []int
+	pools           []*sync.Pool
+)
+
+func init() {
+	for n := minPooledSize; n <= maxPooledSize; n *= 2 {
+		poolSizeClasses = append(poolSizeClasses, n)
+	}
+	pools = make([]*sync.Pool, len(poolSizeClasses))
+	for i, n := range poolSizeClasses {
+		n := n
+		pools[i] = &sync.Pool{
+			New: func() interface{} {
+				return make([]byte, n)
+			},
+		}
+	}
+}
+
+// classFor returns the index of the smallest pool size class that can
+// hold size bytes, or -1 if size exceeds the largest class.
+func classFor(size int) int {
+	for i, n := range poolSizeClasses {
+		if n >= size {
+			return i
+		}
+	}
+	return -1
+}
+
+// PooledBuffer is a Buffer whose backing slice is borrowed from a
+// size-classed sync.Pool instead of being supplied by the caller. It
+// offers the same fixed-capacity write semantics as Buffer while
+// eliminating per-request allocation in high-throughput marshaling paths.
+type PooledBuffer struct {
+	Buffer
+	class int
+	size  int
+}
+
+// GetBuffer returns a PooledBuffer with capacity at least size, borrowing
+// its backing slice from the smallest pool size class that fits. Sizes
+// larger than the largest class fall back to make and are not returned
+// to any pool by Put or Close.
+func GetBuffer(size int) *PooledBuffer {
+	p := &PooledBuffer{class: classFor(size), size: size}
+	p.acquire()
+	return p
+}
+
+func (p *PooledBuffer) acquire() {
+	if p.class < 0 {
+		p.buf = make([]byte, p.size)
+		return
+	}
+	p.buf = pools[p.class].Get().([]byte)
+}
+
+func (p *PooledBuffer) release() {
+	if p.buf == nil {
+		return
+	}
+	if p.class >= 0 {
+		pools[p.class].Put(p.buf)
+	}
+	p.buf = nil
+}
+
+// Reset releases the buffer's backing slab back to its size class pool
+// and rewinds the embedded Buffer (write/read offsets, unread state, and
+// any attached hash). A new slab is acquired lazily the next time the
+// buffer is written to.
+func (p *PooledBuffer) Reset() {
+	p.release()
+	p.Buffer.Reset()
+}
+
+// Close releases buf's backing slab back to its size class pool. buf must
+// not be used after Close returns. It is the explicit counterpart to Put.
+func (p *PooledBuffer) Close() error {
+	p.release()
+	return nil
+}
+
+// Put releases buf's backing slab back to its size class pool. buf must
+// not be used after Put returns.
+func Put(buf *PooledBuffer) {
+	buf.release()
+}
+
+func (p *PooledBuffer) ensure() {
+	if p.buf == nil {
+		p.acquire()
+	}
+}
+
+// Write appends the contents of data to the buffer, acquiring a backing
+// slab first if Reset has released it. See Buffer.Write.
+func (p *PooledBuffer) Write(data []byte) (int, error) {
+	p.ensure()
+	return p.Buffer.Write(data)
+}
+
+// WriteString appends the contents of s to the buffer, acquiring a
+// backing slab first if Reset has released it. See Buffer.WriteString.
+func (p *PooledBuffer) WriteString(s string) (int, error) {
+	p.ensure()
+	return p.Buffer.WriteString(s)
+}
+
+// WriteByte appends the byte c to the buffer, acquiring a backing slab
+// first if Reset has released it. See Buffer.WriteByte.
+func (p *PooledBuffer) WriteByte(c byte) error {
+	p.ensure()
+	return p.Buffer.WriteByte(c)
+}
+
+// WriteRune appends the UTF-8 encoding of r to the buffer, acquiring a
+// backing slab first if Reset has released it. See Buffer.WriteRune.
+func (p *PooledBuffer) WriteRune(r rune) (int, error) {
+	p.ensure()
+	return p.Buffer.WriteRune(r)
+}
+
+// ReadFrom reads data from r into the buffer, acquiring a backing slab
+// first if Reset has released it. See Buffer.ReadFrom.
+func (p *PooledBuffer) ReadFrom(r io.Reader) (int64, error) {
+	p.ensure()
+	return p.Buffer.ReadFrom(r)
+}
@@ -0,0 +1,106 @@
+package gofixbuf_test
+
+import (
+	. "github.com/waucka/gofixbuf"
+	"hash/crc32"
+	"testing"
+)
+
+func TestGetBufferSizing(t *testing.T) {
+	buf := GetBuffer(100)
+	if buf.Cap() < 100 {
+		t.Errorf("expected capacity of at least 100; got %d", buf.Cap())
+	}
+	Put(buf)
+}
+
+func TestGetBufferOversized(t *testing.T) {
+	buf := GetBuffer(8 * 1024 * 1024)
+	if buf.Cap() != 8*1024*1024 {
+		t.Errorf("expected exact fallback capacity of %d; got %d", 8*1024*1024, buf.Cap())
+	}
+	Put(buf)
+}
+
+func TestPooledBufferWriteAndReset(t *testing.T) {
+	buf := GetBuffer(16)
+	n, err := buf.Write([]byte("hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 5 {
+		t.Errorf("expected to write 5 bytes; wrote %d", n)
+	}
+
+	buf.Reset()
+
+	n, err = buf.Write([]byte("world"))
+	if err != nil {
+		t.Fatalf("unexpected error after Reset: %s", err)
+	}
+	if n != 5 {
+		t.Errorf("expected to write 5 bytes after Reset; wrote %d", n)
+	}
+	Put(buf)
+}
+
+func TestPooledBufferResetRewindsReadCursor(t *testing.T) {
+	buf := GetBuffer(16)
+	buf.WriteString("hello")
+	buf.Read(make([]byte, 3))
+
+	buf.Reset()
+
+	buf.WriteString("world")
+	out := make([]byte, 5)
+	n, err := buf.Read(out)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(out[:n]) != "world" {
+		t.Errorf("expected to read back \"world\" after Reset; got %q", out[:n])
+	}
+	Put(buf)
+}
+
+func TestPooledBufferResetRewindsAttachedHash(t *testing.T) {
+	buf := GetBuffer(16)
+	buf.AttachHash(crc32.NewIEEE())
+	buf.WriteString("hello")
+
+	buf.Reset()
+	buf.WriteString("world")
+
+	want := crc32.ChecksumIEEE([]byte("world"))
+	got := buf.Sum()
+	gotVal := uint32(got[0])<<24 | uint32(got[1])<<16 | uint32(got[2])<<8 | uint32(got[3])
+	if gotVal != want {
+		t.Errorf("expected sum of \"world\" (%08x) after Reset; got %08x", want, gotVal)
+	}
+	Put(buf)
+}
+
+func BenchmarkPooledBufferParallel(b *testing.B) {
+	const size = 1024
+	payload := make([]byte, size)
+	b.SetBytes(size)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := GetBuffer(size)
+			buf.Write(payload)
+			Put(buf)
+		}
+	})
+}
+
+func BenchmarkPlainBufferParallel(b *testing.B) {
+	const size = 1024
+	payload := make([]byte, size)
+	b.SetBytes(size)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := NewBuffer(make([]byte, size))
+			buf.Write(payload)
+		}
+	})
+}
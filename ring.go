@@ -0,0 +1,276 @@
+package gofixbuf
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// NewRingBuffer creates a Buffer backed by buf that operates in ring
+// (circular) mode: once the buffer fills, further writes wrap around and
+// overwrite the oldest bytes instead of returning ErrTooLarge. This makes
+// Buffer usable as a bounded log or telemetry sink where only the last
+// len(buf) bytes matter.
+func NewRingBuffer(buf []byte) *Buffer {
+	return &Buffer{buf: buf, ring: true}
+}
+
+// ringLen returns the number of logically valid bytes currently held by a
+// ring-mode Buffer.
+func (b *Buffer) ringLen() int {
+	switch {
+	case b.full:
+		return cap(b.buf)
+	case b.tail >= b.head:
+		return b.tail - b.head
+	default:
+		return cap(b.buf) - b.head + b.tail
+	}
+}
+
+// writeRing copies p into the ring, wrapping around and overwriting the
+// oldest bytes as needed. It never errors; n is always len(p).
+func (b *Buffer) writeRing(p []byte) (n int, err error) {
+	total := len(p)
+	cp := cap(b.buf)
+	if cp == 0 || total == 0 {
+		return total, nil
+	}
+
+	if total >= cp {
+		copy(b.buf, p[total-cp:])
+		b.head, b.tail, b.full = 0, 0, true
+		return total, nil
+	}
+
+	first := cp - b.tail
+	if first > total {
+		first = total
+	}
+	copy(b.buf[b.tail:], p[:first])
+	if rem := total - first; rem > 0 {
+		copy(b.buf, p[first:])
+	}
+
+	overwritten := 0
+	if b.full {
+		overwritten = total
+	} else if avail := cp - b.ringLen(); total > avail {
+		overwritten = total - avail
+	}
+	b.tail = (b.tail + total) % cp
+	if overwritten > 0 {
+		b.head = (b.head + overwritten) % cp
+	}
+	if b.tail == b.head {
+		b.full = true
+	}
+	return total, nil
+}
+
+// readRing copies up to len(p) unread bytes from the ring's logical
+// window into p, in oldest-first order.
+func (b *Buffer) readRing(p []byte) (n int, err error) {
+	avail := b.ringLen() - b.roff
+	if avail <= 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	cp := cap(b.buf)
+	n = len(p)
+	if n > avail {
+		n = avail
+	}
+	start := (b.head + b.roff) % cp
+	first := cp - start
+	if first > n {
+		first = n
+	}
+	copy(p[:first], b.buf[start:start+first])
+	if rem := n - first; rem > 0 {
+		copy(p[first:n], b.buf[:rem])
+	}
+	b.roff += n
+	return n, nil
+}
+
+// ringBytes returns a freshly allocated slice holding the ring's logical
+// contents, oldest byte first. Unlike Buffer.Bytes in linear mode, this
+// cannot alias the underlying array when the ring has wrapped.
+func (b *Buffer) ringBytes() []byte {
+	n := b.ringLen()
+	out := make([]byte, n)
+	if n == 0 {
+		return out
+	}
+	cp := cap(b.buf)
+	first := cp - b.head
+	if first > n {
+		first = n
+	}
+	copy(out, b.buf[b.head:b.head+first])
+	if rem := n - first; rem > 0 {
+		copy(out[first:], b.buf[:rem])
+	}
+	return out
+}
+
+// writeToRing writes the ring's unread logical contents to w as at most
+// two contiguous segments, oldest first.
+func (b *Buffer) writeToRing(w io.Writer) (int64, error) {
+	n := b.ringLen() - b.roff
+	if n <= 0 {
+		return 0, nil
+	}
+	cp := cap(b.buf)
+	start := (b.head + b.roff) % cp
+	first := cp - start
+	if first > n {
+		first = n
+	}
+
+	written, err := w.Write(b.buf[start : start+first])
+	b.roff += written
+	total := int64(written)
+	if err != nil || written < first {
+		return total, err
+	}
+
+	if rem := n - first; rem > 0 {
+		w2, err2 := w.Write(b.buf[:rem])
+		b.roff += w2
+		total += int64(w2)
+		return total, err2
+	}
+	return total, nil
+}
+
+// readByteRing returns the next unread byte from the ring's logical
+// window, in oldest-first order.
+func (b *Buffer) readByteRing() (byte, error) {
+	if b.roff >= b.ringLen() {
+		return 0, io.EOF
+	}
+	cp := cap(b.buf)
+	c := b.buf[(b.head+b.roff)%cp]
+	b.roff++
+	return c, nil
+}
+
+// readRuneRing decodes the next unread UTF-8 rune from the ring's
+// logical window, copying out up to utf8.UTFMax bytes first since a
+// multi-byte rune may straddle the physical wrap point.
+func (b *Buffer) readRuneRing() (r rune, size int, err error) {
+	avail := b.ringLen() - b.roff
+	if avail <= 0 {
+		return 0, 0, io.EOF
+	}
+	cp := cap(b.buf)
+	start := (b.head + b.roff) % cp
+	c := b.buf[start]
+	if c < utf8.RuneSelf {
+		b.roff++
+		return rune(c), 1, nil
+	}
+	n := avail
+	if n > utf8.UTFMax {
+		n = utf8.UTFMax
+	}
+	tmp := make([]byte, n)
+	for i := 0; i < n; i++ {
+		tmp[i] = b.buf[(start+i)%cp]
+	}
+	r, size = utf8.DecodeRune(tmp)
+	b.roff += size
+	return r, size, nil
+}
+
+// readSliceRing reads until delim or the end of the ring's logical
+// window, returning a freshly allocated slice since the window may wrap.
+func (b *Buffer) readSliceRing(delim byte) (line []byte, err error) {
+	avail := b.ringLen() - b.roff
+	cp := cap(b.buf)
+	n := avail
+	found := false
+	for i := 0; i < avail; i++ {
+		if b.buf[(b.head+b.roff+i)%cp] == delim {
+			n = i + 1
+			found = true
+			break
+		}
+	}
+	if !found {
+		err = io.EOF
+	}
+	line = make([]byte, n)
+	for i := 0; i < n; i++ {
+		line[i] = b.buf[(b.head+b.roff+i)%cp]
+	}
+	b.roff += n
+	return line, err
+}
+
+// nextRing returns a freshly allocated slice containing the next n
+// unread bytes from the ring's logical window, advancing the read
+// cursor. If fewer than n bytes remain unread, it returns all of them.
+func (b *Buffer) nextRing(n int) []byte {
+	avail := b.ringLen() - b.roff
+	if n > avail {
+		n = avail
+	}
+	if n < 0 {
+		n = 0
+	}
+	cp := cap(b.buf)
+	data := make([]byte, n)
+	for i := 0; i < n; i++ {
+		data[i] = b.buf[(b.head+b.roff+i)%cp]
+	}
+	b.roff += n
+	return data
+}
+
+// truncateRing discards all but the n oldest logically valid bytes by
+// pulling tail back to n bytes past head. It panics if n is negative or
+// greater than the number of bytes currently held.
+func (b *Buffer) truncateRing(n int) {
+	length := b.ringLen()
+	if n < 0 || n > length {
+		panic("gofixbuf.Buffer: truncation out of range")
+	}
+	cp := cap(b.buf)
+	if cp == 0 {
+		return
+	}
+	b.tail = (b.head + n) % cp
+	b.full = n == cp
+	if b.roff > n {
+		b.roff = n
+	}
+}
+
+// readFromRing keeps reading from r, feeding each chunk into the ring and
+// silently overwriting the oldest bytes, until r returns EOF.
+func (b *Buffer) readFromRing(r io.Reader) (int64, error) {
+	cp := cap(b.buf)
+	if cp == 0 {
+		cp = 512
+	}
+	chunk := make([]byte, cp)
+	var total int64
+	for {
+		m, err := r.Read(chunk)
+		if m > 0 {
+			b.writeRing(chunk[:m])
+			b.feedHash(chunk[:m])
+			total += int64(m)
+		}
+		if err != nil {
+			if err == io.EOF {
+				return total, nil
+			}
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package gofixbuf_test
+
+import (
+	. "github.com/waucka/gofixbuf"
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestRingBufferFillsWithoutError(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+
+	n, err := buf.Write([]byte("ab"))
+	if err != nil || n != 2 {
+		t.Fatalf("unexpected write result: n=%d, err=%v", n, err)
+	}
+	if buf.Len() != 2 {
+		t.Errorf("expected Len() == 2; got %d", buf.Len())
+	}
+	if buf.Cap() != 4 {
+		t.Errorf("expected Cap() == 4; got %d", buf.Cap())
+	}
+}
+
+func TestRingBufferWraps(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+
+	buf.Write([]byte("abcd"))
+	if buf.String() != "abcd" {
+		t.Errorf("expected \"abcd\"; got %q", buf.String())
+	}
+
+	n, err := buf.Write([]byte("ef"))
+	if err != nil || n != 2 {
+		t.Fatalf("unexpected write result: n=%d, err=%v", n, err)
+	}
+	if buf.Len() != 4 {
+		t.Errorf("expected Len() == 4 (full); got %d", buf.Len())
+	}
+	if buf.String() != "cdef" {
+		t.Errorf("expected \"cdef\" after wrapping; got %q", buf.String())
+	}
+}
+
+func TestRingBufferOverwriteLargerThanCapacity(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+
+	buf.Write([]byte("abcdefgh"))
+	if buf.String() != "efgh" {
+		t.Errorf("expected only the last 4 bytes to survive; got %q", buf.String())
+	}
+}
+
+func TestRingBufferWriteTo(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+	buf.Write([]byte("abcdef"))
+
+	var out bytes.Buffer
+	n, err := buf.WriteTo(&out)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 4 {
+		t.Errorf("expected to write 4 bytes; wrote %d", n)
+	}
+	if out.String() != "cdef" {
+		t.Errorf("expected \"cdef\"; got %q", out.String())
+	}
+}
+
+func TestRingBufferReadBytesDoesNotPanic(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 8))
+	buf.Write([]byte("abcd\nef"))
+	buf.Read(make([]byte, 2))
+
+	line, err := buf.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(line) != "cd\n" {
+		t.Errorf("expected \"cd\\n\"; got %q", line)
+	}
+}
+
+func TestRingBufferReadByte(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+	buf.Write([]byte("abcdef")) // wraps; logical contents are "cdef"
+
+	for _, want := range []byte("cdef") {
+		c, err := buf.ReadByte()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if c != want {
+			t.Errorf("expected %q; got %q", want, c)
+		}
+	}
+	if _, err := buf.ReadByte(); err != io.EOF {
+		t.Errorf("expected io.EOF; got %v", err)
+	}
+}
+
+func TestRingBufferReadRuneAndUnreadRune(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+	buf.Write([]byte("abc"))
+	buf.Write([]byte("é")) // 2-byte rune; lands split across the physical wrap point
+
+	buf.ReadByte() // discard "b"
+	buf.ReadByte() // discard "c"; the next rune now straddles index 3 -> 0
+
+	r, size, err := buf.ReadRune()
+	if err != nil || r != 'é' || size != 2 {
+		t.Fatalf("expected ('é', 2, nil); got (%q, %d, %v)", r, size, err)
+	}
+
+	if err := buf.UnreadRune(); err != nil {
+		t.Fatalf("unexpected error from UnreadRune: %s", err)
+	}
+
+	r, size, err = buf.ReadRune()
+	if err != nil || r != 'é' || size != 2 {
+		t.Fatalf("expected to re-read ('é', 2); got (%q, %d, %v)", r, size, err)
+	}
+
+	if _, _, err := buf.ReadRune(); err != io.EOF {
+		t.Errorf("expected io.EOF; got %v", err)
+	}
+}
+
+func TestRingBufferNext(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+	buf.Write([]byte("abcdef")) // logical contents are "cdef"
+
+	got := buf.Next(2)
+	if string(got) != "cd" {
+		t.Errorf("expected \"cd\"; got %q", got)
+	}
+	got = buf.Next(100)
+	if string(got) != "ef" {
+		t.Errorf("expected \"ef\"; got %q", got)
+	}
+}
+
+func TestRingBufferTruncate(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+	buf.Write([]byte("abcdef")) // logical contents are "cdef"
+
+	buf.Truncate(2)
+	if buf.Len() != 2 {
+		t.Errorf("expected Len() == 2; got %d", buf.Len())
+	}
+	if buf.String() != "cd" {
+		t.Errorf("expected \"cd\"; got %q", buf.String())
+	}
+
+	n, err := buf.Write([]byte("XY"))
+	if err != nil || n != 2 {
+		t.Fatalf("unexpected write result: n=%d, err=%v", n, err)
+	}
+	if buf.String() != "cdXY" {
+		t.Errorf("expected \"cdXY\"; got %q", buf.String())
+	}
+}
+
+func TestRingBufferTruncateOutOfRangePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Truncate(n) with n > Len() to panic")
+		}
+	}()
+	buf := NewRingBuffer(make([]byte, 4))
+	buf.Write([]byte("ab"))
+	buf.Truncate(3)
+}
+
+func TestRingBufferReadFrom(t *testing.T) {
+	buf := NewRingBuffer(make([]byte, 4))
+	src := bytes.NewBufferString("abcdefgh")
+
+	n, err := buf.ReadFrom(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 8 {
+		t.Errorf("expected to read 8 bytes; read %d", n)
+	}
+	if buf.String() != "efgh" {
+		t.Errorf("expected only the last 4 bytes to survive; got %q", buf.String())
+	}
+}
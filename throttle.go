@@ -0,0 +1,128 @@
+package gofixbuf
+
+import (
+	"context"
+	"io"
+)
+
+// defaultThrottleChunkSize is the chunk size ThrottledBuffer uses between
+// calls to Limiter.WaitN when ChunkSize is unset.
+const defaultThrottleChunkSize = 4096
+
+// Waiter is satisfied by rate limiters that can block until n units of
+// work are permitted to proceed, honoring ctx cancellation. *rate.Limiter
+// from golang.org/x/time/rate implements it; ThrottledBuffer accepts the
+// interface instead of a hard dependency on that package.
+type Waiter interface {
+	WaitN(ctx context.Context, n int) error
+}
+
+// ThrottledBuffer wraps a Buffer so that Write, WriteString, and ReadFrom
+// chunk their input and call Limiter.WaitN before copying each chunk,
+// giving callers backpressure when feeding a Buffer from an untrusted
+// io.Reader (e.g. a network peer), which Buffer's unconditional ReadFrom
+// cannot provide.
+type ThrottledBuffer struct {
+	*Buffer
+	Limiter Waiter
+
+	// ChunkSize caps how many bytes are copied between WaitN calls. If
+	// zero or negative, defaultThrottleChunkSize is used.
+	ChunkSize int
+}
+
+// NewThrottledBuffer wraps buf so that writes to it are paced by limiter.
+func NewThrottledBuffer(buf *Buffer, limiter Waiter) *ThrottledBuffer {
+	return &ThrottledBuffer{Buffer: buf, Limiter: limiter}
+}
+
+func (t *ThrottledBuffer) chunkSize() int {
+	if t.ChunkSize > 0 {
+		return t.ChunkSize
+	}
+	return defaultThrottleChunkSize
+}
+
+// remainingCap returns the number of bytes that can still be written to
+// the underlying fixed Buffer before it returns ErrTooLarge, or -1 if the
+// Buffer is in ring mode and therefore never fills.
+func (t *ThrottledBuffer) remainingCap() int {
+	if t.Buffer.ring {
+		return -1
+	}
+	return cap(t.Buffer.buf) - t.Buffer.off
+}
+
+// Write chunks p, waiting on t.Limiter before copying each chunk into the
+// underlying Buffer. It returns the number of bytes actually written and
+// stops early, returning ctx.Err(), if ctx is canceled. Chunks are capped
+// to the buffer's remaining capacity: the part of p that fits is written
+// (after waiting on the limiter), and once the buffer is full Write
+// returns the count written and ErrTooLarge without waiting on the
+// limiter for the rest.
+func (t *ThrottledBuffer) Write(ctx context.Context, p []byte) (int, error) {
+	chunk := t.chunkSize()
+	var written int
+	for written < len(p) {
+		n := chunk
+		if rem := len(p) - written; n > rem {
+			n = rem
+		}
+		if remCap := t.remainingCap(); remCap >= 0 && n > remCap {
+			if remCap == 0 {
+				return written, ErrTooLarge
+			}
+			n = remCap
+		}
+		if err := t.Limiter.WaitN(ctx, n); err != nil {
+			return written, err
+		}
+		m, err := t.Buffer.Write(p[written : written+n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// WriteString is the string counterpart to Write.
+func (t *ThrottledBuffer) WriteString(ctx context.Context, s string) (int, error) {
+	return t.Write(ctx, []byte(s))
+}
+
+// ReadFrom reads from r in rate-limited chunks and appends the result to
+// the underlying Buffer until r returns EOF, ctx is canceled, or the
+// buffer fills. Unlike Buffer.ReadFrom, it stops and returns ctx.Err() on
+// cancellation instead of reading to completion.
+func (t *ThrottledBuffer) ReadFrom(ctx context.Context, r io.Reader) (int64, error) {
+	size := t.chunkSize()
+	chunk := make([]byte, size)
+	var total int64
+	for {
+		readBuf := chunk
+		if remCap := t.remainingCap(); remCap >= 0 && remCap < size {
+			if remCap == 0 {
+				return total, ErrTooLarge
+			}
+			readBuf = chunk[:remCap]
+		}
+		m, rerr := r.Read(readBuf)
+		if m > 0 {
+			if err := t.Limiter.WaitN(ctx, m); err != nil {
+				return total, err
+			}
+			n, werr := t.Buffer.Write(chunk[:m])
+			total += int64(n)
+			if werr != nil {
+				return total, werr
+			}
+		}
+		if rerr != nil {
+			if rerr == io.EOF {
+				return total, nil
+			}
+			return total, rerr
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package gofixbuf_test
+
+import (
+	. "github.com/waucka/gofixbuf"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// unlimitedWaiter never blocks; it records each n and returns ctx.Err(),
+// so it errors only once ctx is canceled. It exists to exercise
+// ThrottledBuffer's chunking logic without a real rate limiter dependency.
+type unlimitedWaiter struct {
+	calls []int
+}
+
+func (w *unlimitedWaiter) WaitN(ctx context.Context, n int) error {
+	w.calls = append(w.calls, n)
+	return ctx.Err()
+}
+
+func TestThrottledBufferWriteChunks(t *testing.T) {
+	waiter := &unlimitedWaiter{}
+	tb := NewThrottledBuffer(NewBuffer(make([]byte, 16)), waiter)
+	tb.ChunkSize = 4
+
+	n, err := tb.Write(context.Background(), []byte("0123456789"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 10 {
+		t.Errorf("expected to write 10 bytes; wrote %d", n)
+	}
+	if tb.String() != "0123456789"+string(make([]byte, 6)) {
+		t.Errorf("unexpected buffer contents: %q", tb.String())
+	}
+	if len(waiter.calls) != 3 {
+		t.Errorf("expected 3 WaitN calls (4,4,2); got %v", waiter.calls)
+	}
+}
+
+func TestThrottledBufferWriteTooLarge(t *testing.T) {
+	waiter := &unlimitedWaiter{}
+	tb := NewThrottledBuffer(NewBuffer(make([]byte, 4)), waiter)
+
+	_, err := tb.Write(context.Background(), []byte("hello"))
+	if err != ErrTooLarge {
+		t.Errorf("expected ErrTooLarge; got %v", err)
+	}
+}
+
+func TestThrottledBufferWriteContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	waiter := &unlimitedWaiter{}
+	tb := NewThrottledBuffer(NewBuffer(make([]byte, 16)), waiter)
+
+	n, err := tb.Write(ctx, []byte("hello"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled; got %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected 0 bytes written; wrote %d", n)
+	}
+}
+
+func TestThrottledBufferReadFrom(t *testing.T) {
+	waiter := &unlimitedWaiter{}
+	tb := NewThrottledBuffer(NewBuffer(make([]byte, 16)), waiter)
+	tb.ChunkSize = 4
+
+	src := bytes.NewBufferString("0123456789")
+	n, err := tb.ReadFrom(context.Background(), src)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 10 {
+		t.Errorf("expected to read 10 bytes; read %d", n)
+	}
+}
@@ -0,0 +1,118 @@
+package gofixbuf
+
+import "encoding/binary"
+
+// xxHash64 is a minimal, dependency-free implementation of the xxHash64
+// algorithm (https://github.com/Cyan4973/xxHash), used by NewXXHashBuffer
+// so this package doesn't need a hard dependency on a third-party xxHash
+// module. It buffers all written data and recomputes the digest on Sum,
+// trading the reference implementation's streaming efficiency for a
+// self-contained implementation; this is acceptable given Buffer's
+// fixed, bounded-size use case.
+type xxHash64 struct {
+	seed uint64
+	buf  []byte
+}
+
+func newXXHash64(seed uint64) *xxHash64 {
+	return &xxHash64{seed: seed}
+}
+
+func (x *xxHash64) Write(p []byte) (int, error) {
+	x.buf = append(x.buf, p...)
+	return len(p), nil
+}
+
+func (x *xxHash64) Sum(b []byte) []byte {
+	h := xxh64Sum(x.buf, x.seed)
+	return append(b,
+		byte(h>>56), byte(h>>48), byte(h>>40), byte(h>>32),
+		byte(h>>24), byte(h>>16), byte(h>>8), byte(h))
+}
+
+func (x *xxHash64) Reset() { x.buf = x.buf[:0] }
+
+func (x *xxHash64) Size() int { return 8 }
+
+func (x *xxHash64) BlockSize() int { return 32 }
+
+const (
+	xxh64Prime1 = 0x9E3779B185EBCA87
+	xxh64Prime2 = 0xC2B2AE3D27D4EB4F
+	xxh64Prime3 = 0x165667B19E3779F9
+	xxh64Prime4 = 0x85EBCA77C2B2AE63
+	xxh64Prime5 = 0x27D4EB2F165667C5
+)
+
+// xxh64Sum computes the xxHash64 digest of data with the given seed,
+// following the reference algorithm.
+func xxh64Sum(data []byte, seed uint64) uint64 {
+	n := len(data)
+	var h64 uint64
+	var i int
+
+	if n >= 32 {
+		v1 := seed + xxh64Prime1 + xxh64Prime2
+		v2 := seed + xxh64Prime2
+		v3 := seed
+		v4 := seed - xxh64Prime1
+		for ; i+32 <= n; i += 32 {
+			v1 = xxh64Round(v1, binary.LittleEndian.Uint64(data[i:]))
+			v2 = xxh64Round(v2, binary.LittleEndian.Uint64(data[i+8:]))
+			v3 = xxh64Round(v3, binary.LittleEndian.Uint64(data[i+16:]))
+			v4 = xxh64Round(v4, binary.LittleEndian.Uint64(data[i+24:]))
+		}
+		h64 = xxh64Rotl(v1, 1) + xxh64Rotl(v2, 7) + xxh64Rotl(v3, 12) + xxh64Rotl(v4, 18)
+		h64 = xxh64MergeRound(h64, v1)
+		h64 = xxh64MergeRound(h64, v2)
+		h64 = xxh64MergeRound(h64, v3)
+		h64 = xxh64MergeRound(h64, v4)
+	} else {
+		h64 = seed + xxh64Prime5
+	}
+
+	h64 += uint64(n)
+
+	for ; i+8 <= n; i += 8 {
+		k1 := xxh64Round(0, binary.LittleEndian.Uint64(data[i:]))
+		h64 ^= k1
+		h64 = xxh64Rotl(h64, 27)*xxh64Prime1 + xxh64Prime4
+	}
+
+	if i+4 <= n {
+		h64 ^= uint64(binary.LittleEndian.Uint32(data[i:])) * xxh64Prime1
+		h64 = xxh64Rotl(h64, 23)*xxh64Prime2 + xxh64Prime3
+		i += 4
+	}
+
+	for ; i < n; i++ {
+		h64 ^= uint64(data[i]) * xxh64Prime5
+		h64 = xxh64Rotl(h64, 11) * xxh64Prime1
+	}
+
+	h64 ^= h64 >> 33
+	h64 *= xxh64Prime2
+	h64 ^= h64 >> 29
+	h64 *= xxh64Prime3
+	h64 ^= h64 >> 32
+
+	return h64
+}
+
+func xxh64Round(acc, input uint64) uint64 {
+	acc += input * xxh64Prime2
+	acc = xxh64Rotl(acc, 31)
+	acc *= xxh64Prime1
+	return acc
+}
+
+func xxh64MergeRound(acc, val uint64) uint64 {
+	val = xxh64Round(0, val)
+	acc ^= val
+	acc = acc*xxh64Prime1 + xxh64Prime4
+	return acc
+}
+
+func xxh64Rotl(x uint64, r uint) uint64 {
+	return (x << r) | (x >> (64 - r))
+}